@@ -0,0 +1,122 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// goMainGenerator fills in a minimal runnable main.go stub.
+type goMainGenerator struct{}
+
+func (goMainGenerator) Name() string { return "go-main" }
+
+func (goMainGenerator) Generate(req GenRequest) (GenResponse, error) {
+	if req.Language != "go" || req.Path != "main.go" {
+		return GenResponse{}, nil
+	}
+	contents := fmt.Sprintf(`package main
+
+import "fmt"
+
+func main() {
+	fmt.Println("%s")
+}
+`, req.ProjectRoot)
+	return GenResponse{Files: []GenFile{{Path: "main.go", Contents: contents, Mode: 0644}}}, nil
+}
+
+// goModGenerator fills in a go.mod declaring the project as its own module.
+type goModGenerator struct{}
+
+func (goModGenerator) Name() string { return "go-mod" }
+
+func (goModGenerator) Generate(req GenRequest) (GenResponse, error) {
+	if req.Path != "go.mod" {
+		return GenResponse{}, nil
+	}
+	contents := fmt.Sprintf("module %s\n\ngo 1.21\n", req.ProjectRoot)
+	return GenResponse{Files: []GenFile{{Path: "go.mod", Contents: contents, Mode: 0644}}}, nil
+}
+
+// readmeGenerator fills in a starter README titled after the project root
+// and summarizing the prompt that produced it.
+type readmeGenerator struct{}
+
+func (readmeGenerator) Name() string { return "readme" }
+
+func (readmeGenerator) Generate(req GenRequest) (GenResponse, error) {
+	if !strings.EqualFold(req.Path, "README.md") {
+		return GenResponse{}, nil
+	}
+	contents := fmt.Sprintf("# %s\n\n%s\n", req.ProjectRoot, req.Prompt)
+	return GenResponse{Files: []GenFile{{Path: "README.md", Contents: contents, Mode: 0644}}}, nil
+}
+
+// gitignoreGenerator fills in a .gitignore appropriate to the detected
+// language of the sibling files around it.
+type gitignoreGenerator struct{}
+
+func (gitignoreGenerator) Name() string { return "gitignore" }
+
+func (gitignoreGenerator) Generate(req GenRequest) (GenResponse, error) {
+	if req.Path != ".gitignore" {
+		return GenResponse{}, nil
+	}
+
+	lang := req.Language
+	if lang == "" {
+		lang = languageFromSiblings(req.Siblings)
+	}
+
+	var contents string
+	switch lang {
+	case "go":
+		contents = "# Go\n*.exe\n*.test\n*.out\nvendor/\n"
+	case "python":
+		contents = "# Python\n__pycache__/\n*.pyc\n.venv/\n"
+	case "javascript", "typescript":
+		contents = "# Node\nnode_modules/\ndist/\n.env\n"
+	case "rust":
+		contents = "# Rust\ntarget/\n*.rlib\nCargo.lock\n"
+	default:
+		contents = "*.log\n.env\n"
+	}
+	return GenResponse{Files: []GenFile{{Path: ".gitignore", Contents: contents, Mode: 0644}}}, nil
+}
+
+// languageFromSiblings guesses a project's language from its sibling file
+// names when the .gitignore node itself has no extension to go on.
+func languageFromSiblings(siblings []string) string {
+	for _, s := range siblings {
+		switch {
+		case strings.HasSuffix(s, ".go") || s == "go.mod":
+			return "go"
+		case s == "package.json":
+			return "javascript"
+		case strings.HasSuffix(s, ".py") || s == "requirements.txt":
+			return "python"
+		case s == "Cargo.toml":
+			return "rust"
+		}
+	}
+	return ""
+}
+
+// packageJSONGenerator fills in a minimal package.json named after the
+// project root.
+type packageJSONGenerator struct{}
+
+func (packageJSONGenerator) Name() string { return "package-json" }
+
+func (packageJSONGenerator) Generate(req GenRequest) (GenResponse, error) {
+	if req.Path != "package.json" {
+		return GenResponse{}, nil
+	}
+	contents := fmt.Sprintf(`{
+  "name": "%s",
+  "version": "0.1.0",
+  "private": true
+}
+`, strings.ToLower(req.ProjectRoot))
+	return GenResponse{Files: []GenFile{{Path: "package.json", Contents: contents, Mode: 0644}}}, nil
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kingl0w/ScaffGen/internal/session"
+)
+
+// GenRequest is the JSON payload ScaffGen writes to the stdin of a
+// scaffgen-gen-<name> plugin for every FileNode it asks the plugin to
+// populate. It mirrors the protoc-gen-go request/response convention:
+// one self-contained request in, one response out, no further IPC.
+type GenRequest struct {
+	Path        string   `json:"path"`
+	IsDir       bool     `json:"is_dir"`
+	Siblings    []string `json:"siblings"`
+	ProjectRoot string   `json:"project_root"`
+	Prompt      string   `json:"prompt"`
+	Language    string   `json:"language"`
+}
+
+// GenFile is a single file a generator wants written. Path is relative to
+// the directory holding the node the generator was invoked on: the node's
+// own name targets that node, and any other path lets a plugin drop a
+// sibling (or, with "/", deeper) file that was never in the LLM tree,
+// e.g. asking for "main.go" but also returning "LICENSE".
+type GenFile struct {
+	Path     string `json:"path"`
+	Contents string `json:"contents"`
+	Mode     uint32 `json:"mode"`
+}
+
+// GenResponse is what a generator hands back after Generate.
+type GenResponse struct {
+	Files []GenFile `json:"files"`
+}
+
+// Generator produces content (and optionally extra sibling files) for a
+// FileNode. Built-ins run in-process; anything not in the built-in
+// registry is resolved to an external scaffgen-gen-<name> binary on
+// $PATH that speaks the same JSON request/response over stdio.
+type Generator interface {
+	Name() string
+	Generate(req GenRequest) (GenResponse, error)
+}
+
+var builtinGenerators = map[string]Generator{}
+
+func registerGenerator(g Generator) {
+	builtinGenerators[g.Name()] = g
+}
+
+func init() {
+	registerGenerator(goMainGenerator{})
+	registerGenerator(goModGenerator{})
+	registerGenerator(readmeGenerator{})
+	registerGenerator(gitignoreGenerator{})
+	registerGenerator(packageJSONGenerator{})
+}
+
+// resolveGenerator returns the built-in generator registered under name,
+// or wraps scaffgen-gen-<name> from $PATH if no built-in matches.
+func resolveGenerator(name string) (Generator, error) {
+	if g, ok := builtinGenerators[name]; ok {
+		return g, nil
+	}
+	binName := "scaffgen-gen-" + name
+	path, err := exec.LookPath(binName)
+	if err != nil {
+		return nil, fmt.Errorf("no built-in generator %q and %s not found on $PATH", name, binName)
+	}
+	return subprocessGenerator{name: name, path: path}, nil
+}
+
+// subprocessGenerator shells out to a third-party scaffgen-gen-<name>
+// binary, serializing the request to its stdin and parsing its stdout as
+// a GenResponse. This is the extension point for generators that can't
+// ship in the ScaffGen binary.
+type subprocessGenerator struct {
+	name string
+	path string
+}
+
+func (s subprocessGenerator) Name() string { return s.name }
+
+func (s subprocessGenerator) Generate(req GenRequest) (GenResponse, error) {
+	reqBytes, err := json.Marshal(req)
+	if err != nil {
+		return GenResponse{}, fmt.Errorf("marshalling request for %s: %w", s.name, err)
+	}
+
+	cmd := exec.Command(s.path)
+	cmd.Stdin = bytes.NewReader(reqBytes)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return GenResponse{}, fmt.Errorf("running %s: %w (stderr: %s)", s.path, err, stderr.String())
+	}
+
+	var resp GenResponse
+	if err := json.Unmarshal(stdout.Bytes(), &resp); err != nil {
+		return GenResponse{}, fmt.Errorf("parsing response from %s: %w", s.path, err)
+	}
+	return resp, nil
+}
+
+// detectLanguage makes a best-effort guess at a node's language from its
+// file extension, for plugins that want to branch on it.
+func detectLanguage(name string) string {
+	switch filepath.Ext(name) {
+	case ".go":
+		return "go"
+	case ".py":
+		return "python"
+	case ".js", ".jsx":
+		return "javascript"
+	case ".ts", ".tsx":
+		return "typescript"
+	case ".rs":
+		return "rust"
+	case ".java":
+		return "java"
+	case ".rb":
+		return "ruby"
+	default:
+		return ""
+	}
+}
+
+// applyGenerators runs each named generator over every file node in s.Root
+// (directories are walked but get no request of their own),
+// merging generated content and mode back onto the matching node and
+// splicing any extra sibling files the generator returned into the tree.
+func applyGenerators(s *session.Session, names []string, debug bool) error {
+	var generators []Generator
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		g, err := resolveGenerator(name)
+		if err != nil {
+			return err
+		}
+		generators = append(generators, g)
+	}
+
+	root := s.Root
+	var walk func(node *session.FileNode)
+	walk = func(node *session.FileNode) {
+		if node == nil || node.IsDir {
+			for _, child := range node.Children {
+				walk(child)
+			}
+			return
+		}
+
+		req := GenRequest{
+			Path:        nodePath(node),
+			IsDir:       node.IsDir,
+			Siblings:    siblingNames(node),
+			ProjectRoot: root.Name,
+			Prompt:      s.Prompt,
+			Language:    detectLanguage(node.Name),
+		}
+
+		for _, g := range generators {
+			resp, err := g.Generate(req)
+			if err != nil {
+				if debug {
+					fmt.Printf("\033[33mDebug: generator %q failed for %s: %v\033[0m\n", g.Name(), req.Path, err)
+				}
+				continue
+			}
+			for _, gf := range resp.Files {
+				mergeGenFileIntoTree(s, root, node, gf)
+			}
+		}
+	}
+	walk(root)
+	return nil
+}
+
+// nodePath reconstructs a node's path relative to the project root by
+// walking Parent pointers, stopping short of the root itself so built-ins
+// like goMainGenerator can match on the bare "main.go" they'd see in any
+// other project, rather than on a path prefixed with the root's name.
+func nodePath(node *session.FileNode) string {
+	var parts []string
+	for n := node; n != nil && n.Parent != nil; n = n.Parent {
+		parts = append([]string{n.Name}, parts...)
+	}
+	return filepath.Join(parts...)
+}
+
+// siblingNames lists the names of a node's siblings (itself excluded) so
+// a generator can avoid clobbering or can reference them, e.g. a go.mod
+// generator noticing a main.go already present.
+func siblingNames(node *session.FileNode) []string {
+	if node.Parent == nil {
+		return nil
+	}
+	var names []string
+	for _, sibling := range node.Parent.Children {
+		if sibling == node {
+			continue
+		}
+		names = append(names, sibling.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// mergeGenFileIntoTree writes a generator's output into the tree. If
+// gf.Path is the bare name of the node the generator was invoked on, it
+// targets that node; any other path is resolved from that node's parent,
+// creating missing intermediate directories and the final file as
+// siblings of that node so plugins can add files the LLM tree never
+// mentioned (e.g. LICENSE next to go.mod). A final file that already
+// exists at that path is left as it is; its content is not replaced.
+func mergeGenFileIntoTree(s *session.Session, root, invokedOn *session.FileNode, gf GenFile) {
+	cleanPath := filepath.ToSlash(filepath.Clean(gf.Path))
+	if cleanPath == "." || cleanPath == "" {
+		return
+	}
+
+	if !strings.Contains(cleanPath, "/") && cleanPath == invokedOn.Name {
+		invokedOn.Content = gf.Contents
+		if gf.Mode != 0 {
+			invokedOn.Mode = gf.Mode
+		}
+		return
+	}
+
+	parent := invokedOn.Parent
+	if parent == nil {
+		parent = root
+	}
+
+	segments := strings.Split(cleanPath, "/")
+	current := parent
+	for i, seg := range segments {
+		isLast := i == len(segments)-1
+		var existing *session.FileNode
+		for _, child := range current.Children {
+			if child.Name == seg {
+				existing = child
+				break
+			}
+		}
+		if existing != nil {
+			current = existing
+			continue
+		}
+
+		newNode := &session.FileNode{
+			ID:     s.NextNodeID(),
+			Name:   seg,
+			IsDir:  !isLast,
+			Parent: current,
+			Depth:  current.Depth + 1,
+		}
+		if isLast {
+			newNode.Content = gf.Contents
+			newNode.Mode = gf.Mode
+		}
+		current.Children = append(current.Children, newNode)
+		current = newNode
+	}
+}
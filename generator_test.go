@@ -0,0 +1,59 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/kingl0w/ScaffGen/internal/layout"
+	"github.com/kingl0w/ScaffGen/internal/session"
+)
+
+// TestApplyGeneratorsPopulatesRootFiles reproduces a normal run: a named
+// root (as the LLM prompt mandates) with main.go, go.mod, README.md, and
+// .gitignore directly underneath it. Every built-in generator should
+// match its node by root-relative path and actually write content,
+// rather than leaving every file empty because nodePath included the
+// root's own name as the first path segment.
+func TestApplyGeneratorsPopulatesRootFiles(t *testing.T) {
+	manifest := []byte(`{
+  "name": "myproject",
+  "is_dir": true,
+  "children": [
+    {"name": "main.go"},
+    {"name": "go.mod"},
+    {"name": "README.md"},
+    {"name": ".gitignore"}
+  ]
+}`)
+
+	s := session.New(nil, "", "", "", false)
+	s.Prompt = "a small CLI tool"
+	if err := s.ImportManifest(manifest, layout.FormatJSON); err != nil {
+		t.Fatalf("importing manifest: %v", err)
+	}
+
+	names := []string{"go-main", "go-mod", "readme", "gitignore"}
+	if err := applyGenerators(s, names, false); err != nil {
+		t.Fatalf("applyGenerators: %v", err)
+	}
+
+	want := map[string]bool{
+		"main.go":    false,
+		"go.mod":     false,
+		"README.md":  false,
+		".gitignore": false,
+	}
+	for _, child := range s.Root.Children {
+		if _, ok := want[child.Name]; !ok {
+			continue
+		}
+		if child.Content == "" {
+			t.Errorf("%s: expected generated content, got empty", child.Name)
+		}
+		want[child.Name] = true
+	}
+	for name, seen := range want {
+		if !seen {
+			t.Errorf("expected a root child named %s", name)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package layout
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseASCIITree parses a cleaned ASCII tree (the kind an LLM, or the
+// `tree` command, produces) into the common Node intermediate.
+func ParseASCIITree(cleaned string) (*Node, error) {
+	lines := strings.Split(cleaned, "\n")
+	if len(lines) == 0 || strings.TrimSpace(cleaned) == "" {
+		return nil, fmt.Errorf("layout is empty")
+	}
+
+	type stackEntry struct {
+		node  *Node
+		depth int
+	}
+
+	var root *Node
+	var stack []stackEntry
+
+	for i, line := range lines {
+		originalLine := strings.TrimSuffix(line, "\r")
+		trimmedLine := strings.TrimSpace(originalLine)
+
+		if trimmedLine == "" {
+			continue
+		}
+
+		var itemNameWithSuffix string
+		var currentDepth int
+		indentPart := ""
+
+		prefixFound := false
+		treePrefixes := []string{"├── ", "└── "}
+		for _, p := range treePrefixes {
+			if idx := strings.Index(originalLine, p); idx != -1 {
+				itemNameWithSuffix = strings.TrimSpace(originalLine[idx+len(p):])
+				indentPart = originalLine[:idx]
+				prefixFound = true
+				break
+			}
+		}
+
+		if prefixFound {
+			levelChars := 0
+			for _, r := range indentPart {
+				if r == '│' || r == ' ' {
+					levelChars++
+				}
+			}
+			currentDepth = levelChars / 4
+			if itemNameWithSuffix != "" {
+				currentDepth++
+			}
+		} else {
+			if root == nil {
+				itemNameWithSuffix = trimmedLine
+				currentDepth = 0
+			} else {
+				continue
+			}
+		}
+
+		if itemNameWithSuffix == "" {
+			continue
+		}
+
+		newNode := &Node{
+			Name:  strings.TrimSuffix(itemNameWithSuffix, "/"),
+			IsDir: strings.HasSuffix(itemNameWithSuffix, "/"),
+		}
+
+		if root == nil {
+			root = newNode
+			stack = append(stack, stackEntry{root, 0})
+		} else {
+			for len(stack) > 0 && stack[len(stack)-1].depth >= currentDepth {
+				stack = stack[:len(stack)-1]
+			}
+
+			if len(stack) == 0 {
+				return nil, fmt.Errorf("invalid tree structure: could not find parent for line %d: '%s' (depth %d). Structure might have multiple roots or inconsistent indentation", i+1, newNode.Name, currentDepth)
+			}
+
+			parent := stack[len(stack)-1].node
+			parent.Children = append(parent.Children, newNode)
+
+			if newNode.IsDir {
+				stack = append(stack, stackEntry{newNode, currentDepth})
+			}
+		}
+	}
+
+	if root == nil {
+		return nil, fmt.Errorf("failed to parse any valid root node from the layout. The layout might be malformed or empty after cleaning")
+	}
+	return root, nil
+}
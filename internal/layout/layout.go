@@ -0,0 +1,77 @@
+// Package layout parses and serializes project structures into a
+// common intermediate tree. The ASCII-tree parser and the JSON/YAML
+// manifest codec both produce (or consume) a Node graph, so an ASCII
+// tree, a JSON manifest, and a YAML manifest describing the same
+// project all funnel through the same representation before session
+// converts it into a FileNode graph.
+package layout
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Node is the portable intermediate form of a file or directory. The
+// ASCII-tree parser only ever sets Name, IsDir, and Children; Content,
+// Template, Mode, and SymlinkTarget are manifest-only fields that let a
+// JSON/YAML layout carry more than a tree shape.
+type Node struct {
+	Name          string  `json:"name" yaml:"name"`
+	IsDir         bool    `json:"is_dir,omitempty" yaml:"is_dir,omitempty"`
+	Children      []*Node `json:"children,omitempty" yaml:"children,omitempty"`
+	Content       string  `json:"content,omitempty" yaml:"content,omitempty"`
+	Template      string  `json:"template,omitempty" yaml:"template,omitempty"`
+	Mode          uint32  `json:"mode,omitempty" yaml:"mode,omitempty"`
+	SymlinkTarget string  `json:"symlink_target,omitempty" yaml:"symlink_target,omitempty"`
+}
+
+// Format selects the encoding MarshalTree and UnmarshalTree use.
+type Format int
+
+const (
+	FormatJSON Format = iota
+	FormatYAML
+)
+
+// FormatFromPath detects the manifest format from a file's extension,
+// defaulting to JSON for anything that isn't .yaml or .yml.
+func FormatFromPath(path string) Format {
+	lower := strings.ToLower(path)
+	if strings.HasSuffix(lower, ".yaml") || strings.HasSuffix(lower, ".yml") {
+		return FormatYAML
+	}
+	return FormatJSON
+}
+
+// MarshalTree encodes root as a manifest in the given format.
+func MarshalTree(root *Node, format Format) ([]byte, error) {
+	switch format {
+	case FormatYAML:
+		return yaml.Marshal(root)
+	default:
+		return json.MarshalIndent(root, "", "  ")
+	}
+}
+
+// UnmarshalTree decodes a manifest in the given format into a Node
+// graph.
+func UnmarshalTree(data []byte, format Format) (*Node, error) {
+	var root Node
+	var err error
+	switch format {
+	case FormatYAML:
+		err = yaml.Unmarshal(data, &root)
+	default:
+		err = json.Unmarshal(data, &root)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decoding manifest: %w", err)
+	}
+	if root.Name == "" {
+		return nil, fmt.Errorf("manifest root has no name")
+	}
+	return &root, nil
+}
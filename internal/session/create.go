@@ -0,0 +1,65 @@
+package session
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/kingl0w/ScaffGen/internal/sink"
+)
+
+// createNode materializes node (and, recursively, its children) under
+// relPath through out, writing any Content/Mode a content-generator
+// plugin attached. relPath is slash-separated and relative to whatever
+// root out's backend considers its own - a directory on disk, an
+// archive, or a WebDAV collection.
+func createNode(node *FileNode, relPath string, out sink.OutputSink, onCreate func(path string, isDir bool)) error {
+	itemPath := path.Join(relPath, node.Name)
+
+	if node.IsDir {
+		if err := out.Mkdir(itemPath); err != nil {
+			return fmt.Errorf("creating directory %s: %w", itemPath, err)
+		}
+		if onCreate != nil {
+			onCreate(itemPath, true)
+		}
+		for _, child := range node.Children {
+			if err := createNode(child, itemPath, out, onCreate); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if node.SymlinkTarget != "" {
+		if symlinker, ok := out.(sink.Symlinker); ok {
+			if err := symlinker.Symlink(itemPath, node.SymlinkTarget); err != nil {
+				return fmt.Errorf("creating symlink %s -> %s: %w", itemPath, node.SymlinkTarget, err)
+			}
+			if onCreate != nil {
+				onCreate(itemPath, false)
+			}
+			return nil
+		}
+	}
+
+	file, err := out.CreateFile(itemPath, node.Mode)
+	if err != nil {
+		return fmt.Errorf("creating file %s: %w", itemPath, err)
+	}
+
+	if node.Content != "" {
+		if _, err := file.Write([]byte(node.Content)); err != nil {
+			file.Close()
+			return fmt.Errorf("writing content to %s: %w", itemPath, err)
+		}
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("closing %s: %w", itemPath, err)
+	}
+
+	if onCreate != nil {
+		onCreate(itemPath, false)
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+package session
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxUndoDepth bounds the undo ring buffer so a long editing session
+// doesn't keep every snapshot of the tree around forever.
+const maxUndoDepth = 20
+
+// AddChild appends a new node under parent. Its ID is left zero; the
+// caller renumbers the whole tree afterward so every node keeps a
+// stable, greppable ID.
+func (parent *FileNode) AddChild(name string, isDir bool) *FileNode {
+	child := &FileNode{
+		Name:   name,
+		IsDir:  isDir,
+		Parent: parent,
+		Depth:  parent.Depth + 1,
+	}
+	parent.Children = append(parent.Children, child)
+	return child
+}
+
+// Rename changes the node's display name in place.
+func (n *FileNode) Rename(newName string) {
+	n.Name = newName
+}
+
+// Reparent moves n to be a child of newParent, refusing to create a
+// cycle (moving a node under itself or one of its own descendants) or
+// to detach the root, which has no parent.
+func (n *FileNode) Reparent(newParent *FileNode) error {
+	if n.Parent == nil {
+		return fmt.Errorf("cannot reparent the root node")
+	}
+	if !newParent.IsDir {
+		return fmt.Errorf("%s is not a directory", newParent.Name)
+	}
+	for ancestor := newParent; ancestor != nil; ancestor = ancestor.Parent {
+		if ancestor == n {
+			return fmt.Errorf("cannot move %s under its own descendant %s", n.Name, newParent.Name)
+		}
+	}
+
+	oldParent := n.Parent
+	for i, sibling := range oldParent.Children {
+		if sibling == n {
+			oldParent.Children = append(oldParent.Children[:i], oldParent.Children[i+1:]...)
+			break
+		}
+	}
+
+	n.Parent = newParent
+	newParent.Children = append(newParent.Children, n)
+	updateDepth(n)
+	return nil
+}
+
+func updateDepth(n *FileNode) {
+	if n.Parent != nil {
+		n.Depth = n.Parent.Depth + 1
+	} else {
+		n.Depth = 0
+	}
+	for _, child := range n.Children {
+		updateDepth(child)
+	}
+}
+
+// FindByID walks root looking for the node with the given ID.
+func FindByID(root *FileNode, id int) *FileNode {
+	if root == nil {
+		return nil
+	}
+	if root.ID == id {
+		return root
+	}
+	for _, child := range root.Children {
+		if found := FindByID(child, id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// validateNodeName rejects anything that isn't a single path component,
+// since node.Name is joined straight onto the output path by createNode
+// (and, unchanged, into archive entries and WebDAV URLs). Both AddChild
+// and Rename are reachable unauthenticated over HTTP via POST /modify,
+// so a name like "../../../../tmp/evil" or "a/b" must never reach a
+// node - it would let a caller write outside whatever directory
+// resolveOutputDir confined the sink to.
+func validateNodeName(name string) error {
+	if name == "" {
+		return fmt.Errorf("name must not be empty")
+	}
+	if strings.ContainsAny(name, "/\\") {
+		return fmt.Errorf("name %q must not contain a path separator", name)
+	}
+	if name == "." || name == ".." {
+		return fmt.Errorf("name %q is not a valid file or directory name", name)
+	}
+	return nil
+}
+
+// AddChild creates a new file or directory under the node with
+// parentID and renumbers the tree so the new node gets a stable ID.
+func (s *Session) AddChild(parentID int, name string, isDir bool) (*FileNode, error) {
+	if err := validateNodeName(name); err != nil {
+		return nil, err
+	}
+	parent := FindByID(s.Root, parentID)
+	if parent == nil {
+		return nil, fmt.Errorf("node %d not found", parentID)
+	}
+	if !parent.IsDir {
+		return nil, fmt.Errorf("node %d (%s) is not a directory", parentID, parent.Name)
+	}
+	s.pushUndo()
+	child := parent.AddChild(name, isDir)
+	s.renumberIDs()
+	return child, nil
+}
+
+// Rename changes the name of the node with the given ID.
+func (s *Session) Rename(id int, newName string) error {
+	if err := validateNodeName(newName); err != nil {
+		return err
+	}
+	node := FindByID(s.Root, id)
+	if node == nil {
+		return fmt.Errorf("node %d not found", id)
+	}
+	s.pushUndo()
+	node.Rename(newName)
+	return nil
+}
+
+// Reparent moves the node with id to be a child of newParentID.
+func (s *Session) Reparent(id, newParentID int) error {
+	node := FindByID(s.Root, id)
+	if node == nil {
+		return fmt.Errorf("node %d not found", id)
+	}
+	newParent := FindByID(s.Root, newParentID)
+	if newParent == nil {
+		return fmt.Errorf("node %d not found", newParentID)
+	}
+	s.pushUndo()
+	if err := node.Reparent(newParent); err != nil {
+		s.popUndo() //nothing actually changed, don't waste an undo slot
+		return err
+	}
+	s.renumberIDs()
+	return nil
+}
+
+// Undo restores the tree to its state before the most recent mutation.
+// It reports false if there's nothing left to undo.
+func (s *Session) Undo() bool {
+	if len(s.undoStack) == 0 {
+		return false
+	}
+	s.Root = s.undoStack[len(s.undoStack)-1]
+	s.undoStack = s.undoStack[:len(s.undoStack)-1]
+	s.renumberIDs()
+	return true
+}
+
+// pushUndo snapshots the current tree before a mutation.
+func (s *Session) pushUndo() {
+	if s.Root == nil {
+		return
+	}
+	s.undoStack = append(s.undoStack, cloneTree(s.Root, nil))
+	if len(s.undoStack) > maxUndoDepth {
+		s.undoStack = s.undoStack[1:]
+	}
+}
+
+// popUndo discards the most recent snapshot without restoring it, for
+// mutations that turned out to be no-ops.
+func (s *Session) popUndo() {
+	if len(s.undoStack) == 0 {
+		return
+	}
+	s.undoStack = s.undoStack[:len(s.undoStack)-1]
+}
+
+func cloneTree(node *FileNode, parent *FileNode) *FileNode {
+	if node == nil {
+		return nil
+	}
+	clone := &FileNode{
+		ID:            node.ID,
+		Name:          node.Name,
+		IsDir:         node.IsDir,
+		Depth:         node.Depth,
+		Content:       node.Content,
+		Mode:          node.Mode,
+		Template:      node.Template,
+		SymlinkTarget: node.SymlinkTarget,
+		Parent:        parent,
+	}
+	for _, child := range node.Children {
+		clone.Children = append(clone.Children, cloneTree(child, clone))
+	}
+	return clone
+}
+
+// renumberIDs reassigns every node's ID in deterministic pre-order so
+// displayed IDs stay stable and greppable after a mutation, and resets
+// the counter NextNodeID draws from.
+func (s *Session) renumberIDs() {
+	s.nodeIDCounter = 0
+	var walk func(node *FileNode)
+	walk = func(node *FileNode) {
+		if node == nil {
+			return
+		}
+		s.nodeIDCounter++
+		node.ID = s.nodeIDCounter
+		for _, child := range node.Children {
+			walk(child)
+		}
+	}
+	walk(s.Root)
+}
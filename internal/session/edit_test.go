@@ -0,0 +1,176 @@
+package session
+
+import (
+	"testing"
+
+	"github.com/kingl0w/ScaffGen/internal/layout"
+)
+
+func buildTestSession(t *testing.T) *Session {
+	t.Helper()
+	manifest := []byte(`{
+  "name": "proj",
+  "is_dir": true,
+  "children": [
+    {"name": "src", "is_dir": true, "children": [
+      {"name": "main.go"}
+    ]},
+    {"name": "docs", "is_dir": true}
+  ]
+}`)
+	s := New(nil, "", "", "", false)
+	if err := s.ImportManifest(manifest, layout.FormatJSON); err != nil {
+		t.Fatalf("importing manifest: %v", err)
+	}
+	return s
+}
+
+// nodeNamed walks root looking for a node called name, without failing
+// the test if it's absent - undo renumbers every ID on restore, so a
+// node's ID from before a mutation may belong to an entirely different
+// node afterward and can't be used to check whether "the same node" is
+// still there.
+func nodeNamed(root *FileNode, name string) *FileNode {
+	if root == nil {
+		return nil
+	}
+	if root.Name == name {
+		return root
+	}
+	for _, c := range root.Children {
+		if found := nodeNamed(c, name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func findNamed(t *testing.T, root *FileNode, name string) *FileNode {
+	t.Helper()
+	found := nodeNamed(root, name)
+	if found == nil {
+		t.Fatalf("no node named %q in tree", name)
+	}
+	return found
+}
+
+func TestReparentRejectsCycle(t *testing.T) {
+	s := buildTestSession(t)
+	src := findNamed(t, s.Root, "src")
+	docs := findNamed(t, s.Root, "docs")
+
+	// Moving docs under src is fine...
+	if err := docs.Reparent(src); err != nil {
+		t.Fatalf("docs.Reparent(src): unexpected error: %v", err)
+	}
+	// ...but moving src under its own new descendant (docs) must be rejected.
+	if err := src.Reparent(docs); err == nil {
+		t.Fatal("expected an error reparenting src under its own descendant docs, got nil")
+	}
+}
+
+func TestReparentRejectsDetachingRoot(t *testing.T) {
+	s := buildTestSession(t)
+	docs := findNamed(t, s.Root, "docs")
+	if err := s.Root.Reparent(docs); err == nil {
+		t.Fatal("expected an error reparenting the root node, got nil")
+	}
+}
+
+func TestSessionReparentUnknownIDs(t *testing.T) {
+	s := buildTestSession(t)
+	src := findNamed(t, s.Root, "src")
+
+	if err := s.Reparent(9999, src.ID); err == nil {
+		t.Fatal("expected an error reparenting a nonexistent node, got nil")
+	}
+	if err := s.Reparent(src.ID, 9999); err == nil {
+		t.Fatal("expected an error reparenting onto a nonexistent parent, got nil")
+	}
+}
+
+func TestSessionRenameUnknownID(t *testing.T) {
+	s := buildTestSession(t)
+	if err := s.Rename(9999, "whatever"); err == nil {
+		t.Fatal("expected an error renaming a nonexistent node, got nil")
+	}
+}
+
+func TestAddChildRejectsPathSeparatorsAndTraversal(t *testing.T) {
+	s := buildTestSession(t)
+	for _, name := range []string{"../../../../tmp/evil", "a/b", `a\b`, "..", "."} {
+		if _, err := s.AddChild(s.Root.ID, name, false); err == nil {
+			t.Errorf("AddChild(%q): expected an error, got nil", name)
+		}
+	}
+}
+
+func TestRenameRejectsPathSeparatorsAndTraversal(t *testing.T) {
+	s := buildTestSession(t)
+	src := findNamed(t, s.Root, "src")
+	for _, name := range []string{"../escaped", "nested/path"} {
+		if err := s.Rename(src.ID, name); err == nil {
+			t.Errorf("Rename(%q): expected an error, got nil", name)
+		}
+	}
+}
+
+func TestUndoRestoresPreviousTree(t *testing.T) {
+	s := buildTestSession(t)
+	src := findNamed(t, s.Root, "src")
+
+	// add, then undo
+	if _, err := s.AddChild(src.ID, "new_file.go", false); err != nil {
+		t.Fatalf("AddChild: %v", err)
+	}
+	if !s.Undo() {
+		t.Fatal("Undo after AddChild: expected true")
+	}
+	if nodeNamed(s.Root, "new_file.go") != nil {
+		t.Fatal("expected added node to be gone after undo")
+	}
+
+	// rename, then undo
+	mainGo := findNamed(t, s.Root, "main.go")
+	if err := s.Rename(mainGo.ID, "renamed.go"); err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+	if !s.Undo() {
+		t.Fatal("Undo after Rename: expected true")
+	}
+	if findNamed(t, s.Root, "main.go") == nil {
+		t.Fatal("expected original name back after undo")
+	}
+
+	// move, then undo
+	docs := findNamed(t, s.Root, "docs")
+	if err := s.Reparent(mainGo.ID, docs.ID); err != nil {
+		t.Fatalf("Reparent: %v", err)
+	}
+	if !s.Undo() {
+		t.Fatal("Undo after Reparent: expected true")
+	}
+	restored := findNamed(t, s.Root, "main.go")
+	if restored.Parent == nil || restored.Parent.Name != "src" {
+		t.Fatal("expected main.go back under src after undo")
+	}
+
+	// delete, then undo (docs is looked up again: the undo above renumbered the tree)
+	docs = findNamed(t, s.Root, "docs")
+	if !s.DeleteByID(docs.ID) {
+		t.Fatal("DeleteByID: expected true")
+	}
+	if !s.Undo() {
+		t.Fatal("Undo after DeleteByID: expected true")
+	}
+	if findNamed(t, s.Root, "docs") == nil {
+		t.Fatal("expected docs back after undo")
+	}
+
+	// nothing left to undo once the stack is drained
+	for s.Undo() {
+	}
+	if s.Undo() {
+		t.Fatal("expected Undo to return false once the stack is empty")
+	}
+}
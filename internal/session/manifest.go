@@ -0,0 +1,29 @@
+package session
+
+import (
+	"fmt"
+
+	"github.com/kingl0w/ScaffGen/internal/layout"
+)
+
+// ExportManifest serializes the session's tree as a JSON or YAML
+// manifest (per format), for --export or any other caller that wants a
+// portable copy of the current structure.
+func (s *Session) ExportManifest(format layout.Format) ([]byte, error) {
+	if s.Root == nil {
+		return nil, fmt.Errorf("no project structure to export")
+	}
+	return layout.MarshalTree(nodeToLayout(s.Root), format)
+}
+
+// ImportManifest replaces s.Root with the tree decoded from a JSON or
+// YAML manifest, assigning fresh node IDs the same way ParseLayout does.
+func (s *Session) ImportManifest(data []byte, format layout.Format) error {
+	root, err := layout.UnmarshalTree(data, format)
+	if err != nil {
+		return err
+	}
+	s.nodeIDCounter = 0
+	s.Root = s.nodeFromLayout(root, nil)
+	return nil
+}
@@ -0,0 +1,110 @@
+package session
+
+import (
+	"strings"
+
+	"github.com/kingl0w/ScaffGen/internal/layout"
+)
+
+// CleanProjectStructure strips the boilerplate LLMs tend to wrap a tree
+// in ("Here is the structure...", trailing notes, code fences) down to
+// just the tree lines.
+func CleanProjectStructure(content string) string {
+	lines := strings.Split(content, "\n")
+	var cleanedLines []string
+	inStructure := false //helps to skip leading/trailing non-structure text
+
+	for _, line := range lines {
+		trimmedLine := strings.TrimSpace(line)
+		lowerTrimmedLine := strings.ToLower(trimmedLine)
+
+		//skip common boilerplate
+		if strings.HasPrefix(lowerTrimmedLine, "here is") ||
+			strings.HasPrefix(lowerTrimmedLine, "here's") ||
+			strings.HasPrefix(lowerTrimmedLine, "sure, here") ||
+			strings.HasPrefix(lowerTrimmedLine, "certainly, here") ||
+			strings.HasPrefix(lowerTrimmedLine, "the following is") ||
+			strings.HasPrefix(lowerTrimmedLine, "note:") ||
+			strings.HasPrefix(lowerTrimmedLine, "note ") ||
+			strings.HasPrefix(lowerTrimmedLine, "```") {
+			continue
+		}
+		if strings.Contains(lowerTrimmedLine, "suggested structure") ||
+			strings.Contains(lowerTrimmedLine, "you can adjust this") ||
+			strings.Contains(lowerTrimmedLine, "this is just an example") {
+			continue
+		}
+
+		originalLine := strings.TrimSuffix(line, "\r")
+
+		isLikelyRootItem := !strings.ContainsAny(trimmedLine, " ") && (strings.HasSuffix(trimmedLine, "/") || strings.Contains(trimmedLine, "."))
+
+		if strings.ContainsAny(originalLine, "├──└─│") ||
+			(!inStructure && trimmedLine != "" && isLikelyRootItem) ||
+			(inStructure && trimmedLine != "") {
+			cleanedLines = append(cleanedLines, originalLine)
+			inStructure = true
+		}
+	}
+	return strings.Join(cleanedLines, "\n")
+}
+
+func countLines(s string) int {
+	return strings.Count(strings.TrimSpace(s), "\n")
+}
+
+// parseLayoutToNodeTree parses a cleaned ASCII tree through the shared
+// layout.Node intermediate, then converts it into a FileNode graph,
+// assigning IDs via s.NextNodeID so they stay unique alongside any
+// nodes spliced in later by content generators.
+func (s *Session) parseLayoutToNodeTree(cleaned string) (*FileNode, error) {
+	root, err := layout.ParseASCIITree(cleaned)
+	if err != nil {
+		return nil, err
+	}
+	return s.nodeFromLayout(root, nil), nil
+}
+
+// nodeFromLayout converts a layout.Node - parsed from an ASCII tree or
+// decoded from a JSON/YAML manifest - into a FileNode, assigning IDs and
+// wiring parent pointers and depth as it goes.
+func (s *Session) nodeFromLayout(n *layout.Node, parent *FileNode) *FileNode {
+	node := &FileNode{
+		ID:            s.NextNodeID(),
+		Name:          n.Name,
+		IsDir:         n.IsDir,
+		Parent:        parent,
+		Content:       n.Content,
+		Template:      n.Template,
+		Mode:          n.Mode,
+		SymlinkTarget: n.SymlinkTarget,
+	}
+	if parent != nil {
+		node.Depth = parent.Depth + 1
+	}
+	for _, c := range n.Children {
+		node.Children = append(node.Children, s.nodeFromLayout(c, node))
+	}
+	return node
+}
+
+// nodeToLayout converts a FileNode graph back into the portable
+// layout.Node intermediate, for --export and any other caller that needs
+// a manifest rather than a live tree.
+func nodeToLayout(n *FileNode) *layout.Node {
+	if n == nil {
+		return nil
+	}
+	node := &layout.Node{
+		Name:          n.Name,
+		IsDir:         n.IsDir,
+		Content:       n.Content,
+		Template:      n.Template,
+		Mode:          n.Mode,
+		SymlinkTarget: n.SymlinkTarget,
+	}
+	for _, c := range n.Children {
+		node.Children = append(node.Children, nodeToLayout(c))
+	}
+	return node
+}
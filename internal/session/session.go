@@ -0,0 +1,155 @@
+// Package session holds the core prompt -> LLM -> tree -> materialize
+// loop that both the CLI and the HTTP server drive. Neither caller talks
+// to a provider or the filesystem directly; they only call Session
+// methods, so the parse/modify/create logic never forks between the two
+// front ends.
+package session
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kingl0w/ScaffGen/internal/sink"
+)
+
+// FileNode represents a file or directory in the project structure.
+// Parent is excluded from JSON so the tree can be marshalled straight to
+// the browser without tripping over the cycle. Template is reserved for
+// the content-generator subsystem (not yet consumed by any generator);
+// SymlinkTarget, when set, makes Create emit a symlink instead of a file
+// on sinks that implement sink.Symlinker; other sinks get a regular file.
+type FileNode struct {
+	ID            int         `json:"id"`
+	Name          string      `json:"name"`
+	IsDir         bool        `json:"is_dir"`
+	Children      []*FileNode `json:"children,omitempty"`
+	Parent        *FileNode   `json:"-"`
+	Depth         int         `json:"depth"`
+	Content       string      `json:"content,omitempty"`
+	Mode          uint32      `json:"mode,omitempty"`
+	Template      string      `json:"template,omitempty"`
+	SymlinkTarget string      `json:"symlink_target,omitempty"`
+}
+
+// Provider is the subset of the CLI's LayoutProvider interface the
+// session needs to fetch a layout. Declared here rather than imported so
+// this package has no dependency on the root package's HTTP plumbing.
+type Provider interface {
+	Name() string
+	Generate(ctx context.Context, prompt string, opts ProviderOptions) (string, error)
+}
+
+// ProviderOptions mirrors the root package's ProviderOptions; kept as a
+// separate type so session has no import cycle back to package main.
+type ProviderOptions struct {
+	APIKey  string
+	Model   string
+	BaseURL string
+	Debug   bool
+	OnToken func(token string)
+}
+
+// Session holds everything needed to take a prompt from LLM layout
+// through user edits to a materialized project in an OutputSink. The CLI's
+// interactive loop and the HTTP handlers each own their own Session and
+// drive it through the same methods.
+type Session struct {
+	Provider Provider
+	APIKey   string
+	Model    string
+	BaseURL  string
+	Debug    bool
+
+	Prompt string
+	Root   *FileNode
+
+	nodeIDCounter int
+	undoStack     []*FileNode
+}
+
+// New creates a Session bound to a provider and its credentials.
+func New(provider Provider, apiKey, model, baseURL string, debug bool) *Session {
+	return &Session{Provider: provider, APIKey: apiKey, Model: model, BaseURL: baseURL, Debug: debug}
+}
+
+// FetchLayout asks the provider for a fresh layout for the given prompt,
+// forwarding tokens to onToken as they arrive for streaming providers.
+func (s *Session) FetchLayout(ctx context.Context, prompt string, onToken func(string)) (string, error) {
+	return s.Provider.Generate(ctx, prompt, ProviderOptions{
+		APIKey:  s.APIKey,
+		Model:   s.Model,
+		BaseURL: s.BaseURL,
+		Debug:   s.Debug,
+		OnToken: onToken,
+	})
+}
+
+// ParseLayout cleans raw LLM text and parses it into s.Root, replacing
+// whatever tree was there before.
+func (s *Session) ParseLayout(raw string) error {
+	cleaned := CleanProjectStructure(raw)
+	if countLines(cleaned) < 2 && raw != "" {
+		cleaned = raw
+	}
+	s.nodeIDCounter = 0
+	root, err := s.parseLayoutToNodeTree(cleaned)
+	if err != nil {
+		return err
+	}
+	s.Root = root
+	return nil
+}
+
+// NextNodeID hands out the next globally-unique node ID for this
+// session's tree, for callers (e.g. content generators) that splice in
+// nodes the parser never created.
+func (s *Session) NextNodeID() int {
+	s.nodeIDCounter++
+	return s.nodeIDCounter
+}
+
+// DeleteByID removes the node with the given ID (and its subtree) from
+// the session's tree. Deleting the root clears the tree entirely.
+func (s *Session) DeleteByID(id int) bool {
+	if s.Root == nil {
+		return false
+	}
+	s.pushUndo()
+	if s.Root.ID == id {
+		s.Root = nil
+		return true
+	}
+	if !deleteNodeRecursive(s.Root, id) {
+		s.popUndo()
+		return false
+	}
+	s.renumberIDs()
+	return true
+}
+
+func deleteNodeRecursive(parent *FileNode, id int) bool {
+	for i, child := range parent.Children {
+		if child.ID == id {
+			parent.Children = append(parent.Children[:i], parent.Children[i+1:]...)
+			return true
+		}
+		if deleteNodeRecursive(child, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// Create materializes the session's tree through out. onCreate, if
+// non-nil, is called for every directory and file as it's created so
+// callers can render progress without Create itself knowing whether
+// out is writing to local disk, an archive, or a WebDAV server.
+func (s *Session) Create(out sink.OutputSink, onCreate func(path string, isDir bool)) error {
+	if s.Root == nil {
+		return fmt.Errorf("no project structure to create")
+	}
+	if err := createNode(s.Root, "", out, onCreate); err != nil {
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,134 @@
+package sink
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// archiveSink streams a tree into a single .zip or .tar.gz, either a
+// file on disk or, for "-", stdout. zip entries stream straight through;
+// tar entries are buffered in memory first because a tar header has to
+// declare its file's size before the body follows.
+type archiveSink struct {
+	zip *zip.Writer
+
+	tar *tar.Writer
+	gz  *gzip.Writer
+
+	closeUnderlying func() error
+}
+
+// NewArchiveSink opens path (or stdout, for "-") and returns a sink that
+// writes a .zip archive if path ends in .zip, and a .tar.gz archive
+// otherwise.
+func NewArchiveSink(path string) (OutputSink, error) {
+	var out io.Writer
+	closeUnderlying := func() error { return nil }
+
+	if path == "-" {
+		out = os.Stdout
+	} else {
+		file, err := os.Create(path)
+		if err != nil {
+			return nil, fmt.Errorf("creating archive %s: %w", path, err)
+		}
+		out = file
+		closeUnderlying = file.Close
+	}
+
+	s := &archiveSink{closeUnderlying: closeUnderlying}
+	if strings.HasSuffix(strings.ToLower(path), ".zip") {
+		s.zip = zip.NewWriter(out)
+	} else {
+		s.gz = gzip.NewWriter(out)
+		s.tar = tar.NewWriter(s.gz)
+	}
+	return s, nil
+}
+
+// NewZipSinkTo returns a sink that streams a .zip archive directly to
+// w (an open file, an HTTP response body, anything), for callers that
+// already own the destination writer.
+func NewZipSinkTo(w io.Writer) OutputSink {
+	return &archiveSink{zip: zip.NewWriter(w), closeUnderlying: func() error { return nil }}
+}
+
+func (s *archiveSink) Mkdir(path string) error {
+	if s.zip != nil {
+		_, err := s.zip.Create(path + "/")
+		return err
+	}
+	return s.tar.WriteHeader(&tar.Header{
+		Name:     path + "/",
+		Typeflag: tar.TypeDir,
+		Mode:     0755,
+	})
+}
+
+func (s *archiveSink) CreateFile(path string, mode uint32) (io.WriteCloser, error) {
+	if mode == 0 {
+		mode = 0644
+	}
+	if s.zip != nil {
+		header := &zip.FileHeader{Name: path, Method: zip.Deflate}
+		header.SetMode(os.FileMode(mode))
+		w, err := s.zip.CreateHeader(header)
+		if err != nil {
+			return nil, err
+		}
+		return nopCloser{w}, nil
+	}
+	return &tarFileBuffer{tw: s.tar, path: path, mode: mode}, nil
+}
+
+func (s *archiveSink) Close() error {
+	if s.zip != nil {
+		if err := s.zip.Close(); err != nil {
+			return err
+		}
+		return s.closeUnderlying()
+	}
+	if err := s.tar.Close(); err != nil {
+		return err
+	}
+	if err := s.gz.Close(); err != nil {
+		return err
+	}
+	return s.closeUnderlying()
+}
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+// tarFileBuffer buffers a file's content so its size is known before
+// the tar header naming that size has to be written.
+type tarFileBuffer struct {
+	tw   *tar.Writer
+	path string
+	mode uint32
+	buf  bytes.Buffer
+}
+
+func (b *tarFileBuffer) Write(p []byte) (int, error) {
+	return b.buf.Write(p)
+}
+
+func (b *tarFileBuffer) Close() error {
+	if err := b.tw.WriteHeader(&tar.Header{
+		Name:     b.path,
+		Mode:     int64(b.mode),
+		Size:     int64(b.buf.Len()),
+		Typeflag: tar.TypeReg,
+	}); err != nil {
+		return err
+	}
+	_, err := b.tw.Write(b.buf.Bytes())
+	return err
+}
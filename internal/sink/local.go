@@ -0,0 +1,53 @@
+package sink
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// localSink writes a tree onto the local filesystem under base, the
+// same behavior ScaffGen has always had.
+type localSink struct {
+	base string
+}
+
+// NewLocalSink returns an OutputSink that creates files and directories
+// under base (which must already exist).
+func NewLocalSink(base string) OutputSink {
+	return &localSink{base: base}
+}
+
+func (l *localSink) Mkdir(path string) error {
+	return os.MkdirAll(filepath.Join(l.base, filepath.FromSlash(path)), 0755)
+}
+
+func (l *localSink) CreateFile(path string, mode uint32) (io.WriteCloser, error) {
+	full := filepath.Join(l.base, filepath.FromSlash(path))
+	if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+		return nil, err
+	}
+	file, err := os.Create(full)
+	if err != nil {
+		return nil, err
+	}
+	if mode != 0 {
+		if err := file.Chmod(os.FileMode(mode)); err != nil {
+			file.Close()
+			return nil, err
+		}
+	}
+	return file, nil
+}
+
+func (l *localSink) Symlink(path, target string) error {
+	full := filepath.Join(l.base, filepath.FromSlash(path))
+	if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+		return err
+	}
+	return os.Symlink(target, full)
+}
+
+func (l *localSink) Close() error {
+	return nil
+}
@@ -0,0 +1,24 @@
+// Package sink abstracts where a materialized project structure ends
+// up, so session.Create doesn't need to know whether it's writing to
+// local disk, streaming an archive, or PUTting files to a WebDAV
+// server. Every backend implements OutputSink; session writes through
+// whichever one its caller hands it without caring which.
+package sink
+
+import "io"
+
+// OutputSink receives the directories and files session.Create walks a
+// FileNode tree into. Paths are always slash-separated and relative to
+// whatever root the sink was constructed against.
+type OutputSink interface {
+	Mkdir(path string) error
+	CreateFile(path string, mode uint32) (io.WriteCloser, error)
+	Close() error
+}
+
+// Symlinker is implemented by sinks that can create a symlink directly,
+// for FileNodes with a SymlinkTarget set. Sinks that don't implement it
+// (archives, WebDAV) just write the file out as a regular file.
+type Symlinker interface {
+	Symlink(path, target string) error
+}
@@ -0,0 +1,352 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// ProviderOptions carries everything a LayoutProvider needs to talk to its
+// backend, plus an optional callback for incremental rendering while a
+// streaming provider is still receiving tokens.
+type ProviderOptions struct {
+	APIKey  string
+	Model   string
+	BaseURL string
+	Debug   bool
+	OnToken func(token string)
+}
+
+// LayoutProvider turns a user prompt into raw LLM layout text. Streaming
+// implementations (currently just Ollama) call opts.OnToken as partial
+// output arrives instead of only returning once the full response lands.
+type LayoutProvider interface {
+	Name() string
+	Generate(ctx context.Context, prompt string, opts ProviderOptions) (string, error)
+}
+
+// resolveProvider picks a LayoutProvider by name, defaulting to groq to
+// preserve behavior for existing GROQ_API_KEY-based setups.
+func resolveProvider(name string) (LayoutProvider, error) {
+	switch strings.ToLower(name) {
+	case "", "groq":
+		return openAICompatProvider{providerName: "groq", defaultBaseURL: "https://api.groq.com/openai/v1/chat/completions"}, nil
+	case "openai":
+		return openAICompatProvider{providerName: "openai", defaultBaseURL: "https://api.openai.com/v1/chat/completions"}, nil
+	case "anthropic":
+		return anthropicProvider{}, nil
+	case "ollama":
+		return ollamaProvider{}, nil
+	case "offline", "template":
+		return templateProvider{}, nil
+	default:
+		return nil, fmt.Errorf("unknown provider %q (want groq, openai, anthropic, ollama, or offline)", name)
+	}
+}
+
+// credentialsFor resolves the API key and optional base-URL override for a
+// provider from its conventional environment variables.
+func credentialsFor(providerName string) (apiKey, baseURL string) {
+	switch providerName {
+	case "groq":
+		return os.Getenv("GROQ_API_KEY"), os.Getenv("GROQ_API_URL")
+	case "openai":
+		return os.Getenv("OPENAI_API_KEY"), os.Getenv("OPENAI_API_URL")
+	case "anthropic":
+		return os.Getenv("ANTHROPIC_API_KEY"), os.Getenv("ANTHROPIC_API_URL")
+	case "ollama":
+		return "", os.Getenv("OLLAMA_API_URL")
+	default:
+		return "", ""
+	}
+}
+
+// requiresAPIKey reports whether a provider needs GROQ_API_KEY-style
+// credentials before it can be used; ollama and the offline template
+// provider don't.
+func requiresAPIKey(providerName string) bool {
+	switch providerName {
+	case "ollama", "offline", "template":
+		return false
+	default:
+		return true
+	}
+}
+
+// buildLayoutPrompt wraps the user's prompt in the same tree-format
+// instructions regardless of which backend answers it.
+func buildLayoutPrompt(prompt string) string {
+	return fmt.Sprintf(`You are a helpful coding assistant. Based on the following prompt, generate a well structured file and folder layout in a proper tree format with connecting lines.
+
+Please follow these strict formatting rules:
+1.  The root of the project should be explicitly named if the user's prompt implies a project name (e.g., "project-name/").
+2.  Use proper tree characters: '├──' for items that have siblings below them, '└──' for the last item in a directory.
+3.  Use vertical bars '│' for directory indentation.
+4.  Use 4 spaces for each level of indentation.
+5.  ALWAYS use a trailing slash "/" for directory names (e.g., "folder1/", "subfolder/").
+6.  Do NOT use a trailing slash for file names (e.g., "file1.js", "README.md").
+7.  Ensure consistent spacing and format like this example:
+my-project/
+├── src/
+│   ├── main.go
+│   └── utils/
+│       └── helpers.go
+├── tests/
+│   └── main_test.go
+├── .gitignore
+└── README.md
+
+IMPORTANT: ONLY return the tree structure. Do not include any explanations, introductions, or notes. Do not use backticks or any other markdown formatting around the tree.
+
+Prompt: %s`, prompt)
+}
+
+// stripCodeFence trims a leading/trailing ``` fence some models wrap their
+// answer in despite being told not to, along with a stray language tag on
+// the opening fence line.
+func stripCodeFence(content string) string {
+	content = strings.TrimSpace(content)
+	if strings.HasPrefix(content, "```") && strings.HasSuffix(content, "```") {
+		content = strings.TrimPrefix(content, "```")
+		content = strings.TrimSuffix(content, "```")
+		if firstNewline := strings.Index(content, "\n"); firstNewline != -1 {
+			firstLine := strings.TrimSpace(content[:firstNewline])
+			if len(firstLine) > 0 && len(firstLine) < 15 && !strings.ContainsAny(firstLine, "├──└─│/") && !strings.Contains(firstLine, ".") {
+				content = content[firstNewline+1:]
+			}
+		}
+	}
+	return strings.TrimSpace(content)
+}
+
+// openAICompatProvider implements the OpenAI chat-completions schema
+// shared by Groq and OpenAI itself; only the provider name and default
+// base URL differ between them.
+type openAICompatProvider struct {
+	providerName   string
+	defaultBaseURL string
+}
+
+func (p openAICompatProvider) Name() string { return p.providerName }
+
+func (p openAICompatProvider) Generate(ctx context.Context, prompt string, opts ProviderOptions) (string, error) {
+	baseURL := opts.BaseURL
+	if baseURL == "" {
+		baseURL = p.defaultBaseURL
+	}
+
+	reqBody := map[string]interface{}{
+		"model": opts.Model,
+		"messages": []map[string]string{
+			{"role": "user", "content": buildLayoutPrompt(prompt)},
+		},
+		"temperature": 0.2,
+	}
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("marshalling request for %s: %w", p.providerName, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", baseURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("creating request for %s: %w", p.providerName, err)
+	}
+	req.Header.Set("Authorization", "Bearer "+opts.APIKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	if opts.Debug {
+		fmt.Printf("Debug: Sending request to %s...\n", p.providerName)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%s request error: %w", p.providerName, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("reading %s response: %w", p.providerName, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s request failed with status %d: %s", p.providerName, resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("unmarshalling %s response: %w (body: %s)", p.providerName, err, string(body))
+	}
+	if len(result.Choices) == 0 {
+		return "", fmt.Errorf("%s returned no choices (body: %s)", p.providerName, string(body))
+	}
+
+	content := stripCodeFence(result.Choices[0].Message.Content)
+	if opts.OnToken != nil {
+		opts.OnToken(content)
+	}
+	return content, nil
+}
+
+// anthropicProvider talks to the Anthropic Messages API, which uses an
+// x-api-key header and a differently shaped request/response than the
+// OpenAI-compatible backends.
+type anthropicProvider struct{}
+
+func (anthropicProvider) Name() string { return "anthropic" }
+
+func (anthropicProvider) Generate(ctx context.Context, prompt string, opts ProviderOptions) (string, error) {
+	baseURL := opts.BaseURL
+	if baseURL == "" {
+		baseURL = "https://api.anthropic.com/v1/messages"
+	}
+
+	reqBody := map[string]interface{}{
+		"model":      opts.Model,
+		"max_tokens": 4096,
+		"messages": []map[string]string{
+			{"role": "user", "content": buildLayoutPrompt(prompt)},
+		},
+	}
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("marshalling anthropic request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", baseURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("creating anthropic request: %w", err)
+	}
+	req.Header.Set("x-api-key", opts.APIKey)
+	req.Header.Set("anthropic-version", "2023-06-01")
+	req.Header.Set("Content-Type", "application/json")
+
+	if opts.Debug {
+		fmt.Println("Debug: Sending request to anthropic...")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("anthropic request error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("reading anthropic response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("anthropic request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return "", fmt.Errorf("unmarshalling anthropic response: %w (body: %s)", err, string(body))
+	}
+
+	var sb strings.Builder
+	for _, block := range result.Content {
+		if block.Type == "text" {
+			sb.WriteString(block.Text)
+		}
+	}
+
+	content := stripCodeFence(sb.String())
+	if opts.OnToken != nil {
+		opts.OnToken(content)
+	}
+	return content, nil
+}
+
+// ollamaProvider talks to a local Ollama instance's /api/chat endpoint,
+// which streams newline-delimited JSON chunks rather than one blocking
+// response body.
+type ollamaProvider struct{}
+
+func (ollamaProvider) Name() string { return "ollama" }
+
+func (ollamaProvider) Generate(ctx context.Context, prompt string, opts ProviderOptions) (string, error) {
+	baseURL := opts.BaseURL
+	if baseURL == "" {
+		baseURL = "http://localhost:11434/api/chat"
+	}
+
+	reqBody := map[string]interface{}{
+		"model": opts.Model,
+		"messages": []map[string]string{
+			{"role": "user", "content": buildLayoutPrompt(prompt)},
+		},
+		"stream": true,
+	}
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", fmt.Errorf("marshalling ollama request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", baseURL, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", fmt.Errorf("creating ollama request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if opts.Debug {
+		fmt.Println("Debug: Streaming request to ollama...")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("ollama request error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("ollama request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	var sb strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		var chunk struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+			Done bool `json:"done"`
+		}
+		if err := json.Unmarshal(line, &chunk); err != nil {
+			continue //tolerate the rare malformed NDJSON line
+		}
+		if chunk.Message.Content != "" {
+			sb.WriteString(chunk.Message.Content)
+			if opts.OnToken != nil {
+				opts.OnToken(chunk.Message.Content)
+			}
+		}
+		if chunk.Done {
+			break
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("reading ollama stream: %w", err)
+	}
+
+	return stripCodeFence(sb.String()), nil
+}
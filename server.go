@@ -0,0 +1,303 @@
+package main
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/kingl0w/ScaffGen/internal/session"
+	"github.com/kingl0w/ScaffGen/internal/sink"
+)
+
+//go:embed static
+var staticFS embed.FS
+
+// serverState is the in-memory home for every Session a browser tab has
+// open. Sessions never persist across a server restart; this is a
+// scaffolding tool, not a database.
+type serverState struct {
+	mu         sync.Mutex
+	nextID     int
+	sessions   map[string]*session.Session
+	provider   LayoutProvider
+	apiKey     string
+	model      string
+	baseURL    string
+	debug      bool
+	outputRoot string
+}
+
+// runServer starts the HTTP server mode: the same prompt -> LLM -> tree
+// -> materialize loop as the CLI, driven through a JSON API and a small
+// browser UI instead of stdin/stdout.
+func runServer(addr string, provider LayoutProvider, apiKey, model, baseURL string, debug bool) {
+	outputRoot, err := os.Getwd()
+	if err != nil {
+		fmt.Println("Error: could not resolve server working directory:", err)
+		return
+	}
+
+	state := &serverState{
+		sessions:   make(map[string]*session.Session),
+		provider:   provider,
+		apiKey:     apiKey,
+		model:      model,
+		baseURL:    baseURL,
+		debug:      debug,
+		outputRoot: outputRoot,
+	}
+
+	staticContent, err := fs.Sub(staticFS, "static")
+	if err != nil {
+		fmt.Println("Error: static assets missing from build:", err)
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", http.FileServer(http.FS(staticContent)))
+	mux.HandleFunc("/layout", state.handleLayout)
+	mux.HandleFunc("/modify", state.handleModify)
+	mux.HandleFunc("/create", state.handleCreate)
+
+	fmt.Printf("\033[1;32mScaffGen server listening on %s\033[0m\n", addr)
+	log.Fatal(http.ListenAndServe(addr, mux))
+}
+
+func (st *serverState) newSession() (string, *session.Session) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.nextID++
+	id := fmt.Sprintf("sess-%d", st.nextID)
+	s := session.New(sessionProviderAdapter{st.provider}, st.apiKey, st.model, st.baseURL, st.debug)
+	st.sessions[id] = s
+	return id, s
+}
+
+func (st *serverState) session(id string) (*session.Session, bool) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	s, ok := st.sessions[id]
+	return s, ok
+}
+
+// layoutRequest is the POST /layout body: a prompt to send to the LLM,
+// and optionally an existing session_id to re-prompt within.
+type layoutRequest struct {
+	SessionID string `json:"session_id"`
+	Prompt    string `json:"prompt"`
+}
+
+type layoutResponse struct {
+	SessionID string            `json:"session_id"`
+	Root      *session.FileNode `json:"root"`
+}
+
+func (st *serverState) handleLayout(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "POST required", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req layoutRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if req.Prompt == "" {
+		http.Error(w, "prompt is required", http.StatusBadRequest)
+		return
+	}
+
+	sessionID := req.SessionID
+	s, ok := st.session(sessionID)
+	if !ok {
+		sessionID, s = st.newSession()
+	}
+	s.Prompt = req.Prompt
+
+	// /layout is a single request/response call, so a streaming
+	// provider's tokens can't be pushed to the browser mid-flight the
+	// way the CLI redraws its tree in the terminal; still log progress
+	// server-side in debug mode instead of silently discarding it.
+	var onToken func(string)
+	if st.debug {
+		streamed := 0
+		onToken = func(token string) {
+			streamed += len(token)
+			log.Printf("debug: %s streamed %d chars so far for session %s", s.Provider.Name(), streamed, sessionID)
+		}
+	}
+
+	raw, err := s.FetchLayout(context.Background(), req.Prompt, onToken)
+	if err != nil {
+		http.Error(w, "fetching layout: "+err.Error(), http.StatusBadGateway)
+		return
+	}
+	if err := s.ParseLayout(raw); err != nil {
+		http.Error(w, "parsing layout: "+err.Error(), http.StatusUnprocessableEntity)
+		return
+	}
+
+	writeJSON(w, layoutResponse{SessionID: sessionID, Root: s.Root})
+}
+
+// modifyRequest is the POST /modify body. Action is one of "add",
+// "rename", "move", "delete", or "undo"; the remaining fields are
+// interpreted per-action (e.g. "move" uses ID and NewParentID, "add" uses
+// NewParentID, Name, and IsDir, "undo" uses none).
+type modifyRequest struct {
+	SessionID   string `json:"session_id"`
+	Action      string `json:"action"`
+	ID          int    `json:"id"`
+	NewParentID int    `json:"new_parent_id"`
+	Name        string `json:"name"`
+	IsDir       bool   `json:"is_dir"`
+}
+
+func (st *serverState) handleModify(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "POST required", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req modifyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	s, ok := st.session(req.SessionID)
+	if !ok {
+		http.Error(w, "unknown session_id", http.StatusNotFound)
+		return
+	}
+
+	switch req.Action {
+	case "delete":
+		if !s.DeleteByID(req.ID) {
+			http.Error(w, fmt.Sprintf("node %d not found", req.ID), http.StatusNotFound)
+			return
+		}
+	case "add":
+		if req.Name == "" {
+			http.Error(w, "name is required", http.StatusBadRequest)
+			return
+		}
+		if _, err := s.AddChild(req.NewParentID, req.Name, req.IsDir); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	case "rename":
+		if req.Name == "" {
+			http.Error(w, "name is required", http.StatusBadRequest)
+			return
+		}
+		if err := s.Rename(req.ID, req.Name); err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+	case "move":
+		if err := s.Reparent(req.ID, req.NewParentID); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	case "undo":
+		if !s.Undo() {
+			http.Error(w, "nothing to undo", http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, fmt.Sprintf("unsupported action %q", req.Action), http.StatusBadRequest)
+		return
+	}
+
+	writeJSON(w, layoutResponse{SessionID: req.SessionID, Root: s.Root})
+}
+
+// createRequest is the POST /create body. OutputDir materializes the
+// tree on the server's filesystem; an empty OutputDir streams a zip back
+// to the browser instead.
+type createRequest struct {
+	SessionID string `json:"session_id"`
+	OutputDir string `json:"output_dir"`
+}
+
+func (st *serverState) handleCreate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "POST required", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req createRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid JSON body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	s, ok := st.session(req.SessionID)
+	if !ok {
+		http.Error(w, "unknown session_id", http.StatusNotFound)
+		return
+	}
+	if s.Root == nil {
+		http.Error(w, "session has no project structure", http.StatusUnprocessableEntity)
+		return
+	}
+
+	if req.OutputDir != "" {
+		outputDir, err := st.resolveOutputDir(req.OutputDir)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := os.MkdirAll(outputDir, 0755); err != nil {
+			http.Error(w, "creating output directory: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := s.Create(sink.NewLocalSink(outputDir), nil); err != nil {
+			http.Error(w, "creating project structure: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSON(w, map[string]string{"status": "created", "output_dir": outputDir})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+s.Root.Name+`.zip"`)
+	if err := s.Create(sink.NewZipSinkTo(w), nil); err != nil {
+		http.Error(w, "streaming zip: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// resolveOutputDir confines a client-supplied output_dir under the
+// server's working directory: POST /create is unauthenticated, so an
+// absolute path or a ".." escape must never reach os.MkdirAll /
+// sink.NewLocalSink unchanged, or any caller could make the server write
+// anywhere it has permissions.
+func (st *serverState) resolveOutputDir(requested string) (string, error) {
+	cleaned := filepath.Clean(requested)
+	if filepath.IsAbs(cleaned) {
+		return "", fmt.Errorf("output_dir must be a relative path")
+	}
+
+	full := filepath.Join(st.outputRoot, cleaned)
+	rel, err := filepath.Rel(st.outputRoot, full)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("output_dir escapes the server's output root")
+	}
+	return full, nil
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, "encoding response: "+err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"strings"
+)
+
+// templateProvider ships a small curated set of layouts keyed by keywords
+// in the prompt, so ScaffGen still produces something useful with no
+// network access and no API key configured.
+type templateProvider struct{}
+
+func (templateProvider) Name() string { return "offline" }
+
+func (templateProvider) Generate(ctx context.Context, prompt string, opts ProviderOptions) (string, error) {
+	layout := pickTemplate(strings.ToLower(prompt))
+	if opts.OnToken != nil {
+		opts.OnToken(layout)
+	}
+	return layout, nil
+}
+
+// pickTemplate matches prompt keywords against the curated templates,
+// falling back to a generic Go CLI layout when nothing matches.
+func pickTemplate(lowerPrompt string) string {
+	switch {
+	case strings.Contains(lowerPrompt, "react"):
+		return reactTemplate
+	case strings.Contains(lowerPrompt, "flask") || strings.Contains(lowerPrompt, "python"):
+		return flaskTemplate
+	case strings.Contains(lowerPrompt, "express") || strings.Contains(lowerPrompt, "node"):
+		return nodeTemplate
+	default:
+		return goCLITemplate
+	}
+}
+
+const goCLITemplate = `my-project/
+├── cmd/
+│   └── my-project/
+│       └── main.go
+├── internal/
+│   └── app/
+│       └── app.go
+├── go.mod
+├── .gitignore
+└── README.md`
+
+const nodeTemplate = `my-project/
+├── src/
+│   ├── index.js
+│   └── routes/
+│       └── index.js
+├── package.json
+├── .gitignore
+└── README.md`
+
+const flaskTemplate = `my-project/
+├── app/
+│   ├── __init__.py
+│   └── routes.py
+├── requirements.txt
+├── .gitignore
+└── README.md`
+
+const reactTemplate = `my-project/
+├── src/
+│   ├── App.jsx
+│   └── index.jsx
+├── public/
+│   └── index.html
+├── package.json
+├── .gitignore
+└── README.md`